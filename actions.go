@@ -0,0 +1,103 @@
+package deadmod
+
+import (
+	"context"
+	"fmt"
+)
+
+// pluggable notification channels
+
+// Action is a single notification channel a trigger can fire through (HTTP callback,
+// email, Pub/Sub, another Cloud Function, ...). Each trigger can attach several, fired
+// independently so one dead man's switch can, for example, email a friend and post to a
+// webhook at the same time.
+type Action interface {
+	Fire(ctx context.Context, trigger *DeadMansTrigger) error
+}
+
+// ActionConfig is the discriminated-union, wire-and-storage representation of a single
+// action: Type selects which of the type-specific fields below are meaningful. Attempts
+// and LastError are updated in place by runActionWithRetry for observability.
+type ActionConfig struct {
+	Type string `json:"type"`
+
+	// httpAction
+	URL           string `json:"url,omitempty"`
+	Payload       string `json:"payload,omitempty"`
+	SigningSecret string `json:"-"`
+	BearerToken   string `json:"-"`
+
+	// emailAction
+	To      string `json:"to,omitempty"`
+	From    string `json:"from,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body,omitempty"`
+
+	// pubsubAction
+	Topic   string `json:"topic,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// execAction
+	FunctionName    string `json:"functionName,omitempty"`
+	FunctionPayload string `json:"functionPayload,omitempty"`
+
+	RetryPolicy RetryPolicy `json:"retryPolicy"`
+	Attempts    int         `json:"attempts"`
+	LastError   string      `json:"lastError"`
+}
+
+// actionInput mirrors ActionConfig for decoding a creation request, where SigningSecret and
+// BearerToken must be readable even though ActionConfig itself never serializes them back.
+type actionInput struct {
+	Type            string      `json:"type"`
+	URL             string      `json:"url"`
+	Payload         string      `json:"payload"`
+	SigningSecret   string      `json:"signingSecret"`
+	BearerToken     string      `json:"bearerToken"`
+	To              string      `json:"to"`
+	From            string      `json:"from"`
+	Subject         string      `json:"subject"`
+	Body            string      `json:"body"`
+	Topic           string      `json:"topic"`
+	Message         string      `json:"message"`
+	FunctionName    string      `json:"functionName"`
+	FunctionPayload string      `json:"functionPayload"`
+	RetryPolicy     RetryPolicy `json:"retryPolicy"`
+}
+
+func toActionConfig(input actionInput) ActionConfig {
+	return ActionConfig{
+		Type:            input.Type,
+		URL:             input.URL,
+		Payload:         input.Payload,
+		SigningSecret:   input.SigningSecret,
+		BearerToken:     input.BearerToken,
+		To:              input.To,
+		From:            input.From,
+		Subject:         input.Subject,
+		Body:            input.Body,
+		Topic:           input.Topic,
+		Message:         input.Message,
+		FunctionName:    input.FunctionName,
+		FunctionPayload: input.FunctionPayload,
+		RetryPolicy:     input.RetryPolicy,
+	}
+}
+
+// buildAction resolves config into the Action implementation it selects, also serving as
+// the validation step that rejects an unknown type at creation time rather than at fire
+// time.
+func buildAction(config ActionConfig) (Action, error) {
+	switch config.Type {
+	case "http":
+		return &httpAction{config: config}, nil
+	case "email":
+		return &emailAction{config: config}, nil
+	case "pubsub":
+		return &pubsubAction{config: config}, nil
+	case "exec":
+		return &execAction{config: config}, nil
+	default:
+		return nil, fmt.Errorf("unknown action type %q", config.Type)
+	}
+}
@@ -0,0 +1,165 @@
+package deadmod
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// chunked, bounded-concurrency cron job
+
+// IgnoredParameter is the Cloud Functions trigger payload for ServeCron, which fires on a
+// schedule and carries no useful data.
+type IgnoredParameter struct{}
+
+const (
+	// cronPageSize bounds how many due triggers are loaded into memory per Store query,
+	// so the iterator isn't held open across the (potentially slow) callbacks it feeds.
+	cronPageSize = 50
+
+	defaultCronConcurrency = 16
+)
+
+// cronConcurrency reads the worker pool size from DMS_CRON_CONCURRENCY, falling back to
+// defaultCronConcurrency.
+func cronConcurrency() int {
+	if raw := os.Getenv("DMS_CRON_CONCURRENCY"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultCronConcurrency
+}
+
+// ServeCron fires every trigger due by now, paging through the store so a large backlog
+// never holds an iterator open across a slow callback, and fanning out through a bounded
+// worker pool so it never spawns an unbounded number of goroutines. It respects ctx's
+// deadline: if the backlog can't be drained in time, it stops enqueueing new work and
+// returns, leaving the remaining due triggers in the store (their DueToFire untouched) to
+// be picked up by the next invocation instead of timing out mid-flight.
+func ServeCron(ctx context.Context, ignore IgnoredParameter) error {
+	concurrency := cronConcurrency()
+	jobs := make(chan DeadMansTrigger, concurrency)
+
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for trigger := range jobs {
+				if fireErr := fireTrigger(ctx, trigger); fireErr != nil {
+					log.Printf("At least one action failed for %s: %v", trigger.Id, fireErr)
+				}
+			}
+		}()
+	}
+
+	now := time.Now()
+	cursor := ""
+	var err error
+
+paging:
+	for {
+		var batch []DeadMansTrigger
+		batch, cursor, err = store.DueTriggers(ctx, now, cursor, cronPageSize)
+		if err != nil {
+			break
+		}
+
+		for _, trigger := range batch {
+			log.Printf("Enqueuing %s for firing (%d action(s))", trigger.Id, len(trigger.Actions))
+			select {
+			case jobs <- trigger:
+			case <-ctx.Done():
+				break paging
+			}
+		}
+
+		if cursor == "" || ctx.Err() != nil {
+			break
+		}
+	}
+
+	close(jobs)
+	workers.Wait()
+
+	if ctx.Err() != nil {
+		log.Printf("Cron invocation ran out of time; remaining due triggers will be picked up by the next run")
+	}
+
+	return err
+}
+
+// fireTrigger runs every one of trigger's actions concurrently, each retried independently
+// per its own RetryPolicy. trigger is deleted once all actions have concluded, unless one of
+// them was cut short by ctx being canceled or hitting its deadline rather than by its own
+// retry policy being exhausted: in that case trigger (and its in-flight action state) is
+// left untouched in the store, so the next cron invocation picks it back up instead of it
+// being lost mid-flight.
+func fireTrigger(ctx context.Context, trigger DeadMansTrigger) error {
+	var actions sync.WaitGroup
+	errs := make([]error, len(trigger.Actions))
+
+	// put guards every write to trigger.Actions, and every Put of the whole trigger, once the
+	// goroutines below start: each one copies its own ActionConfig (which only it ever
+	// writes) and retries that private copy (so concurrent retries never touch the same
+	// memory), then merges that copy back and persists the whole trigger
+	// while holding put, so one action's finished state is never clobbered by another's
+	// in-flight Put of a stale snapshot.
+	var put sync.Mutex
+
+	for i := range trigger.Actions {
+		actions.Add(1)
+		go func(i int) {
+			defer actions.Done()
+
+			config := trigger.Actions[i]
+			action, buildErr := buildAction(config)
+			if buildErr != nil {
+				errs[i] = buildErr
+				return
+			}
+
+			fireErr := runActionWithRetry(ctx, action, &trigger, &config)
+			errs[i] = fireErr
+			if isContextErr(fireErr) {
+				return
+			}
+
+			put.Lock()
+			trigger.Actions[i] = config
+			if putErr := store.PutTrigger(ctx, &trigger); putErr != nil {
+				log.Printf("Failed to persist action state for %s: %v", trigger.Id, putErr)
+			}
+			put.Unlock()
+		}(i)
+	}
+
+	actions.Wait()
+
+	var lastErr error
+	interrupted := false
+	for i, actionErr := range errs {
+		if actionErr != nil {
+			if isContextErr(actionErr) {
+				interrupted = true
+			}
+			lastErr = fmt.Errorf("action %d (%s): %w", i, trigger.Actions[i].Type, actionErr)
+		}
+	}
+
+	if interrupted {
+		log.Printf("Firing %s was interrupted by context cancellation; leaving it for the next run", trigger.Id)
+		return lastErr
+	}
+
+	if delErr := store.DeleteTrigger(ctx, trigger.Id); delErr != nil {
+		log.Printf("Failed to delete trigger %s after firing: %v", trigger.Id, delErr)
+	}
+
+	return lastErr
+}
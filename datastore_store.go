@@ -0,0 +1,125 @@
+package deadmod
+
+import (
+	"cloud.google.com/go/datastore"
+	"context"
+	"encoding/json"
+	"google.golang.org/api/iterator"
+	"time"
+)
+
+// datastoreStore is the original Cloud Datastore backed Store implementation.
+type datastoreStore struct {
+	client *datastore.Client
+}
+
+// triggerActionsProperty carries DeadMansTrigger.Actions on Datastore entities: Actions is
+// tagged `datastore:"-"` on the struct itself, so it's reflection-invisible to
+// datastore.SaveStruct/LoadStruct, and is instead (de)serialized here as a single JSON blob
+// property. Save/Load make *DeadMansTrigger a datastore.PropertyLoadSaver, which the
+// datastore client detects and uses in place of plain reflection for Get/Put.
+const triggerActionsProperty = "Actions"
+
+func (t *DeadMansTrigger) Save() ([]datastore.Property, error) {
+	props, err := datastore.SaveStruct(t)
+	if err != nil {
+		return nil, err
+	}
+
+	actionsJSON, err := json.Marshal(t.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(props, datastore.Property{
+		Name:    triggerActionsProperty,
+		Value:   string(actionsJSON),
+		NoIndex: true,
+	}), nil
+}
+
+func (t *DeadMansTrigger) Load(props []datastore.Property) error {
+	rest := make([]datastore.Property, 0, len(props))
+	for _, p := range props {
+		if p.Name != triggerActionsProperty {
+			rest = append(rest, p)
+			continue
+		}
+
+		raw, ok := p.Value.(string)
+		if ok && raw != "" {
+			if err := json.Unmarshal([]byte(raw), &t.Actions); err != nil {
+				return err
+			}
+		}
+	}
+
+	return datastore.LoadStruct(t, rest)
+}
+
+func (s *datastoreStore) key(id string) *datastore.Key {
+	return &datastore.Key{Kind: Kind, Name: id}
+}
+
+func (s *datastoreStore) GetTrigger(ctx context.Context, id string) (*DeadMansTrigger, error) {
+	entity := DeadMansTrigger{}
+	err := s.client.Get(ctx, s.key(id), &entity)
+	if err == datastore.ErrNoSuchEntity {
+		return nil, ErrTriggerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (s *datastoreStore) PutTrigger(ctx context.Context, trigger *DeadMansTrigger) error {
+	_, err := s.client.Put(ctx, s.key(trigger.Id), trigger)
+	return err
+}
+
+func (s *datastoreStore) DeleteTrigger(ctx context.Context, id string) error {
+	return s.client.Delete(ctx, s.key(id))
+}
+
+func (s *datastoreStore) DueTriggers(ctx context.Context, now time.Time, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	query := datastore.NewQuery(Kind).Filter("DueToFire <=", now).Limit(limit)
+	return s.runPaged(ctx, query, cursor, limit)
+}
+
+func (s *datastoreStore) TriggersByOwner(ctx context.Context, ownerId string, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	query := datastore.NewQuery(Kind).Filter("OwnerId =", ownerId).Limit(limit)
+	return s.runPaged(ctx, query, cursor, limit)
+}
+
+func (s *datastoreStore) runPaged(ctx context.Context, query *datastore.Query, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	if cursor != "" {
+		decoded, err := datastore.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Start(decoded)
+	}
+
+	it := s.client.Run(ctx, query)
+
+	batch := make([]DeadMansTrigger, 0, limit)
+	target := DeadMansTrigger{}
+	var err error
+	for _, err = it.Next(&target); err == nil; _, err = it.Next(&target) {
+		batch = append(batch, target)
+		target = DeadMansTrigger{}
+	}
+	if err != iterator.Done {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(batch) == limit {
+		if c, cerr := it.Cursor(); cerr == nil {
+			nextCursor = c.String()
+		}
+	}
+
+	return batch, nextCursor, nil
+}
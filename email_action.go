@@ -0,0 +1,70 @@
+package deadmod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// sendgridEndpoint is SendGrid's v3 transactional mail API; emailAction can be pointed at
+// any SendGrid-compatible endpoint (including SES's SMTP-to-HTTP gateways) via this
+// constant's usual override point, DMS_SENDGRID_ENDPOINT.
+const sendgridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// emailAction sends a plain-text email through a SendGrid-style REST API, authenticated
+// with an API key supplied out of band via DMS_SENDGRID_API_KEY.
+type emailAction struct {
+	config ActionConfig
+}
+
+func (a *emailAction) Fire(ctx context.Context, trigger *DeadMansTrigger) error {
+	apiKey := os.Getenv("DMS_SENDGRID_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("DMS_SENDGRID_API_KEY is not configured")
+	}
+
+	endpoint := os.Getenv("DMS_SENDGRID_ENDPOINT")
+	if endpoint == "" {
+		endpoint = sendgridEndpoint
+	}
+
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": a.config.To}}},
+		},
+		"from":    map[string]string{"email": a.config.From},
+		"subject": a.config.Subject,
+		"content": []map[string]string{{"type": "text/plain", "value": a.config.Body}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	rq, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	rq.Header.Set("Content-Type", "application/json")
+	rq.Header.Set("Authorization", "Bearer "+apiKey)
+
+	rsp, err := http.DefaultClient.Do(rq.WithContext(ctx))
+	if err != nil {
+		return &RetryableError{err}
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		statusErr := fmt.Errorf("sendgrid returned status %d for trigger %s", rsp.StatusCode, trigger.Id)
+		if isRetryableStatus(rsp.StatusCode, a.config.RetryPolicy.RetryableStatusClasses) {
+			return &RetryableError{statusErr}
+		}
+		return statusErr
+	}
+
+	return nil
+}
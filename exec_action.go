@@ -0,0 +1,42 @@
+package deadmod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// execAction invokes another Cloud Function in the same project and region by name,
+// mirroring how this function's own invocation URL is assembled in init().
+type execAction struct {
+	config ActionConfig
+}
+
+func (a *execAction) Fire(ctx context.Context, trigger *DeadMansTrigger) error {
+	projectId := os.Getenv("GCP_PROJECT")
+	region := os.Getenv("FUNCTION_REGION")
+	url := fmt.Sprint("https://", region, "-", projectId, ".cloudfunctions.net/", a.config.FunctionName)
+
+	rq, err := http.NewRequest("POST", url, strings.NewReader(a.config.FunctionPayload))
+	if err != nil {
+		return err
+	}
+
+	rsp, err := http.DefaultClient.Do(rq.WithContext(ctx))
+	if err != nil {
+		return &RetryableError{err}
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		statusErr := fmt.Errorf("invoking function %s returned status %d", a.config.FunctionName, rsp.StatusCode)
+		if isRetryableStatus(rsp.StatusCode, a.config.RetryPolicy.RetryableStatusClasses) {
+			return &RetryableError{statusErr}
+		}
+		return statusErr
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package deadmod
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// firestoreStore is a Store implementation backed by Cloud Firestore, selected via
+// DMS_STORE_BACKEND=firestore. Triggers are stored as documents in the "triggers"
+// collection, keyed by trigger id.
+type firestoreStore struct {
+	client     *firestore.Client
+	collection string
+}
+
+func newFirestoreStore(ctx context.Context, projectId string) (*firestoreStore, error) {
+	client, err := firestore.NewClient(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return &firestoreStore{client: client, collection: "triggers"}, nil
+}
+
+func (s *firestoreStore) doc(id string) *firestore.DocumentRef {
+	return s.client.Collection(s.collection).Doc(id)
+}
+
+func (s *firestoreStore) GetTrigger(ctx context.Context, id string) (*DeadMansTrigger, error) {
+	snap, err := s.doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrTriggerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var trigger DeadMansTrigger
+	if err := snap.DataTo(&trigger); err != nil {
+		return nil, err
+	}
+	return &trigger, nil
+}
+
+func (s *firestoreStore) PutTrigger(ctx context.Context, trigger *DeadMansTrigger) error {
+	_, err := s.doc(trigger.Id).Set(ctx, trigger)
+	return err
+}
+
+func (s *firestoreStore) DeleteTrigger(ctx context.Context, id string) error {
+	_, err := s.doc(id).Delete(ctx)
+	return err
+}
+
+func (s *firestoreStore) DueTriggers(ctx context.Context, now time.Time, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	query := s.client.Collection(s.collection).
+		Where("DueToFire", "<=", now).
+		OrderBy("DueToFire", firestore.Asc).
+		Limit(limit)
+
+	if cursor != "" {
+		after, err := time.Parse(time.RFC3339Nano, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.StartAfter(after)
+	}
+
+	batch, err := s.runPaged(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(batch) == limit {
+		nextCursor = batch[len(batch)-1].DueToFire.Format(time.RFC3339Nano)
+	}
+
+	return batch, nextCursor, nil
+}
+
+func (s *firestoreStore) TriggersByOwner(ctx context.Context, ownerId string, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	query := s.client.Collection(s.collection).
+		Where("OwnerId", "==", ownerId).
+		OrderBy("Id", firestore.Asc).
+		Limit(limit)
+
+	if cursor != "" {
+		query = query.StartAfter(cursor)
+	}
+
+	batch, err := s.runPaged(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(batch) == limit {
+		nextCursor = batch[len(batch)-1].Id
+	}
+
+	return batch, nextCursor, nil
+}
+
+func (s *firestoreStore) runPaged(ctx context.Context, query firestore.Query) ([]DeadMansTrigger, error) {
+	it := query.Documents(ctx)
+	defer it.Stop()
+
+	var batch []DeadMansTrigger
+	for {
+		snap, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var trigger DeadMansTrigger
+		if err := snap.DataTo(&trigger); err != nil {
+			return nil, err
+		}
+		batch = append(batch, trigger)
+	}
+
+	return batch, nil
+}
@@ -1,26 +1,22 @@
 package deadmod
 
 import (
-	"cloud.google.com/go/datastore"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/satori/go.uuid"
-	"google.golang.org/api/iterator"
-	"io"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
 // datastore setup and initialization
 
 var (
-	store    *datastore.Client
+	store    Store
 	baseURL  string
 	notFound = StatusCodeError{http.StatusNotFound, "The requested URL could not be found"}
 )
@@ -35,13 +31,8 @@ func init() {
 
 	baseURL = fmt.Sprint("https://", region, "-", projectId, ".cloudfunctions.net/", name)
 
-	client, err := datastore.NewClient(context.Background(), projectId)
-	if err != nil {
-		log.Fatalf("Could not create datastore client: %v", err)
-	} else {
-		log.Printf("Initialized datastore client for project %s with base url %s", projectId, baseURL)
-		store = client
-	}
+	store = newStore(context.Background())
+	log.Printf("Initialized store with base url %s", baseURL)
 }
 
 // http layer
@@ -63,10 +54,13 @@ func HandleHTTP(rw http.ResponseWriter, rq *http.Request) {
 	if length >= 2 && segments[1] == "triggers" {
 		switch length {
 		case 2:
-			if rq.Method == "POST" {
-				err = createTrigger(rq.Context(), rq.Body, rw)
-			} else {
-				err = &StatusCodeError{http.StatusMethodNotAllowed, "The requested method is not available. Available methods: POST"}
+			switch rq.Method {
+			case "POST":
+				err = createTrigger(rq, rw)
+			case "GET":
+				err = listTriggers(rq, rw)
+			default:
+				err = &StatusCodeError{http.StatusMethodNotAllowed, "The requested method is not available. Available methods: GET, POST"}
 			}
 		case 3:
 			if id, e2 := uuid.FromString(segments[2]); e2 == nil {
@@ -119,30 +113,32 @@ func HandleHTTP(rw http.ResponseWriter, rq *http.Request) {
 const Kind = "DMT"
 
 type DeadMansTrigger struct {
-	Id               string      `json:"id" datastore:",noindex"`
-	DueToFire        time.Time   `json:"due"`
-	HoursBetweenFire int         `json:"hoursBetweenFire" datastore:",noindex"`
-	Checkins         []time.Time `json:"checkins" datastore:",noindex"`
-	FireURL          string      `json:"fireURL" datastore:",noindex"`
-	FirePayload      string      `json:"firePayload" datastore:",noindex"`
+	Id               string         `json:"id" datastore:",noindex"`
+	DueToFire        time.Time      `json:"due"`
+	HoursBetweenFire int            `json:"hoursBetweenFire" datastore:",noindex"`
+	Checkins         []time.Time    `json:"checkins" datastore:",noindex"`
+	// Actions is excluded from Datastore's reflection-based (de)serialization: a
+	// RetryPolicy's RetryableStatusClasses is a slice nested inside a slice element, which
+	// Datastore's struct flattening rejects outright. datastoreStore's PropertyLoadSaver
+	// implementation carries it as a single JSON blob property instead.
+	Actions []ActionConfig `json:"actions" datastore:"-"`
+	OwnerId string         `json:"ownerId"`
 }
 
 func checkinTrigger(ctx context.Context, id uuid.UUID, writer http.ResponseWriter) error {
-	entity := DeadMansTrigger{}
-	key := datastore.Key{Kind: Kind, Name: id.String()}
-	err := store.Get(ctx, &key, &entity)
+	entity, err := store.GetTrigger(ctx, id.String())
 
 	if err == nil {
 		now := time.Now().Truncate(time.Second)
 		entity.Checkins = append(entity.Checkins, now)
 		entity.DueToFire = now.Add(time.Duration(entity.HoursBetweenFire * int(time.Hour)))
 
-		_, err = store.Put(ctx, &key, &entity)
+		err = store.PutTrigger(ctx, entity)
 
 		if err == nil {
-			sendEntity(writer, &entity)
+			sendEntity(writer, entity)
 		}
-	} else if err == datastore.ErrNoSuchEntity {
+	} else if err == ErrTriggerNotFound {
 		err = &notFound
 	}
 
@@ -150,8 +146,8 @@ func checkinTrigger(ctx context.Context, id uuid.UUID, writer http.ResponseWrite
 }
 
 func deleteTrigger(ctx context.Context, id uuid.UUID, writer http.ResponseWriter) error {
-	log.Printf("Deleting trigger for %v from datastore", id)
-	err := store.Delete(ctx, &datastore.Key{Kind: Kind, Name: id.String()})
+	log.Printf("Deleting trigger for %v from the store", id)
+	err := store.DeleteTrigger(ctx, id.String())
 	if err == nil {
 		writer.WriteHeader(http.StatusNoContent)
 	}
@@ -159,12 +155,11 @@ func deleteTrigger(ctx context.Context, id uuid.UUID, writer http.ResponseWriter
 }
 
 func getTrigger(ctx context.Context, id uuid.UUID, writer http.ResponseWriter) error {
-	entity := DeadMansTrigger{}
-	err := store.Get(ctx, &datastore.Key{Kind: Kind, Name: id.String()}, &entity)
+	entity, err := store.GetTrigger(ctx, id.String())
 
 	if err == nil {
-		sendEntity(writer, &entity)
-	} else if err == datastore.ErrNoSuchEntity {
+		sendEntity(writer, entity)
+	} else if err == ErrTriggerNotFound {
 		err = &notFound
 	}
 
@@ -176,80 +171,53 @@ func sendEntity(writer http.ResponseWriter, entity *DeadMansTrigger) {
 	_ = json.NewEncoder(writer).Encode(&entity)
 }
 
-func createTrigger(ctx context.Context, body io.ReadCloser, responseWriter http.ResponseWriter) error {
+func createTrigger(rq *http.Request, responseWriter http.ResponseWriter) error {
+	ctx := rq.Context()
 	var input struct {
-		HoursBetweenFire int    `json:"hoursBetweenFire"`
-		FireURL          string `json:"fireURL"`
-		FirePayload      string `json:"firePayload"`
+		HoursBetweenFire int           `json:"hoursBetweenFire"`
+		Actions          []actionInput `json:"actions"`
 	}
 
-	err := json.NewDecoder(body).Decode(&input)
+	if err := json.NewDecoder(rq.Body).Decode(&input); err != nil {
+		return &StatusCodeError{http.StatusUnprocessableEntity, err.Error()}
+	}
 
-	if err == nil {
-		now := time.Now().Truncate(time.Second)
-		fullEntity := DeadMansTrigger{
-			Id:               uuid.NewV4().String(),
-			DueToFire:        now.Add(time.Duration(input.HoursBetweenFire * int(time.Hour))),
-			Checkins:         []time.Time{now},
-			HoursBetweenFire: input.HoursBetweenFire,
-			FirePayload:      input.FirePayload,
-			FireURL:          input.FireURL,
+	actions := make([]ActionConfig, len(input.Actions))
+	for i, in := range input.Actions {
+		if in.SigningSecret != "" && in.BearerToken != "" {
+			return &StatusCodeError{http.StatusUnprocessableEntity, "signingSecret and bearerToken are mutually exclusive"}
 		}
 
-		if _, err = store.Put(ctx, &datastore.Key{Kind: Kind, Name: fullEntity.Id}, &fullEntity); err == nil {
-			path := fmt.Sprint(baseURL, "/triggers/", fullEntity.Id)
-			responseWriter.Header().Add("Location", path)
-			responseWriter.WriteHeader(http.StatusTemporaryRedirect)
-			log.Printf("Inserted entity %s, and redirected user to %s", fullEntity.Id, path)
+		config := toActionConfig(in)
+		if config.RetryPolicy.MaxAttempts <= 0 {
+			config.RetryPolicy = DefaultRetryPolicy
 		}
-	} else {
-		err = &StatusCodeError{http.StatusUnprocessableEntity, err.Error()}
-	}
 
-	return err
-}
+		if _, err := buildAction(config); err != nil {
+			return &StatusCodeError{http.StatusUnprocessableEntity, err.Error()}
+		}
 
-// cronjob logic
-
-type IgnoredParameter struct{}
-
-func ServeCron(ctx context.Context, ignore IgnoredParameter) error {
-	var (
-		err error
-		k   *datastore.Key
-		wg  sync.WaitGroup
-	)
-	query := datastore.NewQuery(Kind).Filter("DueToFire <= ", time.Now())
-	it := store.Run(ctx, query)
-	target := DeadMansTrigger{}
-	for k, err = it.Next(&target); err == nil; _, err = it.Next(&target) {
-		log.Printf("Firing %s callback to %s (async)", target.Id, target.FireURL)
-		wg.Add(1)
-		go fireHttpCallback(ctx, target.FireURL, target.FirePayload, &wg)
-		err = store.Delete(ctx, k)
+		actions[i] = config
 	}
 
-	if err == iterator.Done {
-		err = nil
+	now := time.Now().Truncate(time.Second)
+	fullEntity := DeadMansTrigger{
+		Id:               uuid.NewV4().String(),
+		DueToFire:        now.Add(time.Duration(input.HoursBetweenFire * int(time.Hour))),
+		Checkins:         []time.Time{now},
+		HoursBetweenFire: input.HoursBetweenFire,
+		Actions:          actions,
+		OwnerId:          rq.Header.Get(ownerIdHeader),
 	}
 
-	wg.Wait()
-
-	return err
-}
-
-func fireHttpCallback(ctx context.Context, requestUrl string, body string, group *sync.WaitGroup) {
-	var rsp *http.Response
-	rq, err := http.NewRequest("POST", requestUrl, strings.NewReader(body))
-	if err == nil {
-		if rsp, err = http.DefaultClient.Do(rq.WithContext(ctx)); err == nil {
-			log.Printf("Successfully invoked %s (status code: %d)", requestUrl, rsp.StatusCode)
-		}
+	if err := store.PutTrigger(ctx, &fullEntity); err != nil {
+		return err
 	}
 
-	if err != nil {
-		log.Printf("Failed to invoke %s, error: %v", requestUrl, err)
-	}
+	path := fmt.Sprint(baseURL, "/triggers/", fullEntity.Id)
+	responseWriter.Header().Add("Location", path)
+	responseWriter.WriteHeader(http.StatusTemporaryRedirect)
+	log.Printf("Inserted entity %s, and redirected user to %s", fullEntity.Id, path)
 
-	group.Done()
+	return nil
 }
@@ -0,0 +1,37 @@
+package deadmod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// httpAction POSTs config.Payload to config.URL, signing the request per signing.go.
+type httpAction struct {
+	config ActionConfig
+}
+
+func (a *httpAction) Fire(ctx context.Context, trigger *DeadMansTrigger) error {
+	rq, err := http.NewRequest("POST", a.config.URL, strings.NewReader(a.config.Payload))
+	if err != nil {
+		return err
+	}
+	signRequest(rq, trigger.Id, a.config.Payload, a.config.SigningSecret, a.config.BearerToken)
+
+	rsp, err := http.DefaultClient.Do(rq.WithContext(ctx))
+	if err != nil {
+		return &RetryableError{err}
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		statusErr := fmt.Errorf("callback to %s returned status %d", a.config.URL, rsp.StatusCode)
+		if isRetryableStatus(rsp.StatusCode, a.config.RetryPolicy.RetryableStatusClasses) {
+			return &RetryableError{statusErr}
+		}
+		return statusErr
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package deadmod
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// cursor-paginated listing, scoped to the caller's owner id
+
+// ownerIdHeader carries the caller identity, expected to be populated by an upstream
+// IAP/JWT verifier hook before requests reach this service.
+const ownerIdHeader = "X-Owner-Id"
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+type triggerPage struct {
+	Items      []DeadMansTrigger `json:"items"`
+	NextCursor string            `json:"nextCursor"`
+}
+
+func listTriggers(rq *http.Request, writer http.ResponseWriter) error {
+	ownerId := rq.Header.Get(ownerIdHeader)
+	if ownerId == "" {
+		return &StatusCodeError{http.StatusUnauthorized, "Listing triggers requires the " + ownerIdHeader + " header"}
+	}
+
+	pageSize := defaultPageSize
+	if raw := rq.URL.Query().Get("pageSize"); raw != "" {
+		if parsed, convErr := strconv.Atoi(raw); convErr == nil && parsed > 0 {
+			pageSize = parsed
+		}
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	items, nextCursor, err := store.TriggersByOwner(rq.Context(), ownerId, rq.URL.Query().Get("cursor"), pageSize)
+	if err != nil {
+		return err
+	}
+
+	writer.Header().Add("Content-Type", "application/json")
+	return json.NewEncoder(writer).Encode(&triggerPage{Items: items, NextCursor: nextCursor})
+}
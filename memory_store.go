@@ -0,0 +1,104 @@
+package deadmod
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryStore is an in-memory Store implementation. It lets the package's HTTP and cron
+// logic run and be tested without GCP credentials, and is selected via
+// DMS_STORE_BACKEND=memory.
+type memoryStore struct {
+	mu       sync.Mutex
+	triggers map[string]DeadMansTrigger
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{triggers: make(map[string]DeadMansTrigger)}
+}
+
+func (s *memoryStore) GetTrigger(ctx context.Context, id string) (*DeadMansTrigger, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trigger, ok := s.triggers[id]
+	if !ok {
+		return nil, ErrTriggerNotFound
+	}
+	return &trigger, nil
+}
+
+func (s *memoryStore) PutTrigger(ctx context.Context, trigger *DeadMansTrigger) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.triggers[trigger.Id] = *trigger
+	return nil
+}
+
+func (s *memoryStore) DeleteTrigger(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.triggers, id)
+	return nil
+}
+
+func (s *memoryStore) DueTriggers(ctx context.Context, now time.Time, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matching := make([]DeadMansTrigger, 0)
+	for _, trigger := range s.triggers {
+		if !trigger.DueToFire.After(now) {
+			matching = append(matching, trigger)
+		}
+	}
+	return s.page(matching, cursor, limit)
+}
+
+func (s *memoryStore) TriggersByOwner(ctx context.Context, ownerId string, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matching := make([]DeadMansTrigger, 0)
+	for _, trigger := range s.triggers {
+		if trigger.OwnerId == ownerId {
+			matching = append(matching, trigger)
+		}
+	}
+	return s.page(matching, cursor, limit)
+}
+
+// page sorts matches by Id for a stable order, then slices out limit items starting after
+// cursor (an opaque decimal offset), returning the offset to resume from as the next cursor.
+func (s *memoryStore) page(matches []DeadMansTrigger, cursor string, limit int) ([]DeadMansTrigger, string, error) {
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Id < matches[j].Id })
+
+	offset := 0
+	if cursor != "" {
+		parsed, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		offset = parsed
+	}
+	if offset > len(matches) {
+		offset = len(matches)
+	}
+
+	end := offset + limit
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	nextCursor := ""
+	if end < len(matches) {
+		nextCursor = strconv.Itoa(end)
+	}
+
+	return matches[offset:end], nextCursor, nil
+}
@@ -0,0 +1,59 @@
+package deadmod
+
+import (
+	"cloud.google.com/go/pubsub"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"sync"
+)
+
+// pubsubAction publishes config.Message to a GCP Pub/Sub topic in the function's own
+// project.
+type pubsubAction struct {
+	config ActionConfig
+}
+
+// pubsubClient is built once and reused across every Fire call (and every retry of it),
+// the same way httpAction and emailAction reuse http.DefaultClient, rather than paying for
+// a fresh gRPC connection on every attempt.
+var (
+	pubsubClientOnce sync.Once
+	pubsubClient     *pubsub.Client
+	pubsubClientErr  error
+)
+
+func getPubsubClient(ctx context.Context) (*pubsub.Client, error) {
+	pubsubClientOnce.Do(func() {
+		pubsubClient, pubsubClientErr = pubsub.NewClient(ctx, os.Getenv("GCP_PROJECT"))
+	})
+	return pubsubClient, pubsubClientErr
+}
+
+func (a *pubsubAction) Fire(ctx context.Context, trigger *DeadMansTrigger) error {
+	client, err := getPubsubClient(ctx)
+	if err != nil {
+		return &RetryableError{err}
+	}
+
+	result := client.Topic(a.config.Topic).Publish(ctx, &pubsub.Message{
+		Data: []byte(a.config.Message),
+		Attributes: map[string]string{
+			"triggerId": trigger.Id,
+		},
+	})
+
+	if _, err := result.Get(ctx); err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.PermissionDenied, codes.InvalidArgument:
+			// the topic doesn't exist or we're not allowed to publish to it: no amount of
+			// retrying fixes that.
+			return err
+		default:
+			return &RetryableError{err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package deadmod
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// retry subsystem for actions
+
+// RetryableError marks an Action.Fire failure as transient, so runActionWithRetry will try
+// again; any other error is treated as terminal.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// RetryPolicy controls how runActionWithRetry retries a failed action before giving up.
+// Interval for attempt n is InitialIntervalMs * Multiplier^(n-1), capped at MaxIntervalMs,
+// then jittered uniformly by +/- RandomizationFactor.
+type RetryPolicy struct {
+	MaxAttempts            int     `json:"maxAttempts" datastore:",noindex"`
+	InitialIntervalMs      int     `json:"initialIntervalMs" datastore:",noindex"`
+	MaxIntervalMs          int     `json:"maxIntervalMs" datastore:",noindex"`
+	Multiplier             float64 `json:"multiplier" datastore:",noindex"`
+	RandomizationFactor    float64 `json:"randomizationFactor" datastore:",noindex"`
+	RetryableStatusClasses []int   `json:"retryableStatusClasses" datastore:",noindex"`
+	MaxElapsedMs           int     `json:"maxElapsedMs" datastore:",noindex"`
+}
+
+// DefaultRetryPolicy applies whenever a trigger is created without an explicit one.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:            5,
+	InitialIntervalMs:      500,
+	MaxIntervalMs:          30000,
+	Multiplier:             2,
+	RandomizationFactor:    0.2,
+	RetryableStatusClasses: []int{500, 429},
+	MaxElapsedMs:           5 * 60 * 1000,
+}
+
+// isRetryableStatus reports whether code is covered by classes. A class entry that is an
+// exact multiple of 100 (e.g. 500) matches the whole Nxx family; anything else must match
+// the status code exactly (e.g. 429).
+func isRetryableStatus(code int, classes []int) bool {
+	for _, class := range classes {
+		if class == code {
+			return true
+		}
+		if class%100 == 0 && code/100*100 == class {
+			return true
+		}
+	}
+	return false
+}
+
+// nextBackoff computes the sleep duration before retry attempt n+1, including jitter.
+func nextBackoff(policy RetryPolicy, attempt int) time.Duration {
+	interval := float64(policy.InitialIntervalMs) * math.Pow(policy.Multiplier, float64(attempt-1))
+	if interval > float64(policy.MaxIntervalMs) {
+		interval = float64(policy.MaxIntervalMs)
+	}
+
+	delta := interval * policy.RandomizationFactor
+	jittered := interval - delta + rand.Float64()*2*delta
+	if jittered < 0 {
+		jittered = 0
+	}
+
+	return time.Duration(jittered) * time.Millisecond
+}
+
+// runActionWithRetry fires action, retrying on RetryableError failures according to
+// config.RetryPolicy (defaulted if unset). config.Attempts and config.LastError are
+// updated in memory on every attempt; fireTrigger writes them to the store once the action
+// has concluded, so they are visible via GET while the trigger's other actions are still
+// running. They are not written to the store between attempts.
+func runActionWithRetry(ctx context.Context, action Action, trigger *DeadMansTrigger, config *ActionConfig) error {
+	policy := config.RetryPolicy
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultRetryPolicy
+	}
+	config.RetryPolicy = policy
+
+	// A caller-supplied policy may leave MaxElapsedMs unset without meaning "never retry":
+	// treat that as no wall-clock cap rather than a deadline of "now".
+	var deadline time.Time
+	if policy.MaxElapsedMs > 0 {
+		deadline = time.Now().Add(time.Duration(policy.MaxElapsedMs) * time.Millisecond)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		config.Attempts = attempt
+
+		fireErr := action.Fire(ctx, trigger)
+		if fireErr == nil {
+			config.LastError = ""
+			lastErr = nil
+			break
+		}
+
+		lastErr = fireErr
+		config.LastError = fireErr.Error()
+
+		var retryable *RetryableError
+		if !errors.As(fireErr, &retryable) || attempt == policy.MaxAttempts || (!deadline.IsZero() && time.Now().After(deadline)) {
+			break
+		}
+
+		select {
+		case <-time.After(nextBackoff(policy, attempt)):
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+			config.LastError = lastErr.Error()
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return lastErr
+}
+
+// isContextErr reports whether err stems from ctx being canceled or hitting its deadline,
+// as opposed to the action itself failing. fireTrigger uses this to tell "the cron ran out
+// of time" apart from "the retry policy was exhausted".
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,110 @@
+package deadmod
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// alwaysFailAction fails every call with a RetryableError, so runActionWithRetry keeps
+// retrying until MaxAttempts or MaxElapsedMs stops it.
+type alwaysFailAction struct {
+	calls int
+}
+
+func (a *alwaysFailAction) Fire(ctx context.Context, trigger *DeadMansTrigger) error {
+	a.calls++
+	return &RetryableError{errFail}
+}
+
+var errFail = errors.New("fire failed")
+
+func TestIsRetryableStatus(t *testing.T) {
+	classes := []int{500, 429}
+
+	cases := []struct {
+		code int
+		want bool
+	}{
+		{500, true},
+		{503, true},
+		{429, true},
+		{404, false},
+		{400, false},
+		{200, false},
+	}
+
+	for _, c := range cases {
+		if got := isRetryableStatus(c.code, classes); got != c.want {
+			t.Errorf("isRetryableStatus(%d, %v) = %v, want %v", c.code, classes, got, c.want)
+		}
+	}
+}
+
+func TestNextBackoffGrowsAndCaps(t *testing.T) {
+	policy := RetryPolicy{
+		InitialIntervalMs:   100,
+		MaxIntervalMs:       1000,
+		Multiplier:          2,
+		RandomizationFactor: 0,
+	}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		1000 * time.Millisecond, // capped
+	}
+
+	for attempt, expected := range want {
+		got := nextBackoff(policy, attempt+1)
+		if got != expected {
+			t.Errorf("nextBackoff(policy, %d) = %v, want %v", attempt+1, got, expected)
+		}
+	}
+}
+
+func TestNextBackoffJitterStaysWithinBounds(t *testing.T) {
+	policy := RetryPolicy{
+		InitialIntervalMs:   1000,
+		MaxIntervalMs:       10000,
+		Multiplier:          1,
+		RandomizationFactor: 0.2,
+	}
+
+	min := 800 * time.Millisecond
+	max := 1200 * time.Millisecond
+
+	for i := 0; i < 100; i++ {
+		got := nextBackoff(policy, 1)
+		if got < min || got > max {
+			t.Fatalf("nextBackoff(policy, 1) = %v, want in [%v, %v]", got, min, max)
+		}
+	}
+}
+
+func TestRunActionWithRetryUnsetMaxElapsedMsDoesNotCapAttempts(t *testing.T) {
+	action := &alwaysFailAction{}
+	config := &ActionConfig{
+		RetryPolicy: RetryPolicy{
+			MaxAttempts:         3,
+			InitialIntervalMs:   1,
+			MaxIntervalMs:       1,
+			Multiplier:          1,
+			RandomizationFactor: 0,
+			// MaxElapsedMs intentionally left unset.
+		},
+	}
+
+	trigger := &DeadMansTrigger{Id: "t1"}
+	err := runActionWithRetry(context.Background(), action, trigger, config)
+
+	if action.calls != 3 {
+		t.Fatalf("action.calls = %d, want 3 (MaxAttempts should not be defeated by an unset MaxElapsedMs)", action.calls)
+	}
+	if err == nil {
+		t.Fatal("runActionWithRetry returned nil, want the final failure")
+	}
+}
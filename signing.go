@@ -0,0 +1,42 @@
+package deadmod
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// callback authenticity: HMAC request signing and static bearer tokens
+
+const (
+	headerTimestamp = "X-DMS-Timestamp"
+	headerTriggerId = "X-DMS-Trigger-Id"
+	headerSignature = "X-DMS-Signature"
+)
+
+// signPayload computes HMAC-SHA256(secret, timestamp + "." + body), following the
+// timestamp-dot-body convention used by most webhook providers.
+func signPayload(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signRequest attaches authenticity headers to rq for the httpAction identified by
+// triggerId, if it was configured with a secret or bearerToken. The two are mutually
+// exclusive and enforced as such at creation time; secret wins if both are somehow set.
+func signRequest(rq *http.Request, triggerId string, payload string, secret string, bearerToken string) {
+	if secret != "" {
+		timestamp := fmt.Sprintf("%d", time.Now().Unix())
+		signature := signPayload(secret, timestamp, payload)
+
+		rq.Header.Set(headerTimestamp, timestamp)
+		rq.Header.Set(headerTriggerId, triggerId)
+		rq.Header.Set(headerSignature, "sha256="+signature)
+	} else if bearerToken != "" {
+		rq.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+}
@@ -0,0 +1,59 @@
+package deadmod
+
+import (
+	"cloud.google.com/go/datastore"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+)
+
+// pluggable storage backend
+
+// ErrTriggerNotFound is returned by Store implementations when no trigger exists for the
+// given id.
+var ErrTriggerNotFound = errors.New("trigger not found")
+
+// Store abstracts the persistence operations HandleHTTP and ServeCron need, so the rest of
+// the package doesn't depend on Datastore directly and can be exercised without GCP
+// credentials.
+type Store interface {
+	GetTrigger(ctx context.Context, id string) (*DeadMansTrigger, error)
+	PutTrigger(ctx context.Context, trigger *DeadMansTrigger) error
+	DeleteTrigger(ctx context.Context, id string) error
+
+	// DueTriggers returns up to limit triggers due to fire at or before now, resuming from
+	// cursor (empty for the first page), along with the cursor to resume from next
+	// (empty once there are no more pages).
+	DueTriggers(ctx context.Context, now time.Time, cursor string, limit int) (batch []DeadMansTrigger, nextCursor string, err error)
+
+	// TriggersByOwner mirrors DueTriggers' pagination, scoped to a single owner.
+	TriggersByOwner(ctx context.Context, ownerId string, cursor string, limit int) (batch []DeadMansTrigger, nextCursor string, err error)
+}
+
+// newStore selects a Store implementation based on DMS_STORE_BACKEND ("datastore",
+// "firestore" or "memory"), defaulting to "datastore" to preserve existing deployments.
+func newStore(ctx context.Context) Store {
+	projectId := os.Getenv("GCP_PROJECT")
+
+	switch backend := os.Getenv("DMS_STORE_BACKEND"); backend {
+	case "memory":
+		log.Printf("Using in-memory store backend")
+		return newMemoryStore()
+	case "firestore":
+		fsStore, err := newFirestoreStore(ctx, projectId)
+		if err != nil {
+			log.Fatalf("Could not create firestore client: %v", err)
+		}
+		log.Printf("Initialized firestore store for project %s", projectId)
+		return fsStore
+	default:
+		client, err := datastore.NewClient(ctx, projectId)
+		if err != nil {
+			log.Fatalf("Could not create datastore client: %v", err)
+		}
+		log.Printf("Initialized datastore store for project %s", projectId)
+		return &datastoreStore{client: client}
+	}
+}
@@ -0,0 +1,124 @@
+package deadmod
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// Package-level var initializers run before any init() func, including the package's own
+// init() in handler.go that calls newStore and would otherwise reach for real GCP
+// credentials. This keeps `go test` runnable without them.
+var _ = os.Setenv("DMS_STORE_BACKEND", "memory")
+
+func TestMemoryStoreGetPutDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+
+	if _, err := s.GetTrigger(ctx, "missing"); err != ErrTriggerNotFound {
+		t.Fatalf("GetTrigger(missing) err = %v, want ErrTriggerNotFound", err)
+	}
+
+	trigger := &DeadMansTrigger{Id: "t1", OwnerId: "alice"}
+	if err := s.PutTrigger(ctx, trigger); err != nil {
+		t.Fatalf("PutTrigger: %v", err)
+	}
+
+	got, err := s.GetTrigger(ctx, "t1")
+	if err != nil {
+		t.Fatalf("GetTrigger(t1): %v", err)
+	}
+	if got.OwnerId != "alice" {
+		t.Errorf("GetTrigger(t1).OwnerId = %q, want alice", got.OwnerId)
+	}
+
+	if err := s.DeleteTrigger(ctx, "t1"); err != nil {
+		t.Fatalf("DeleteTrigger: %v", err)
+	}
+	if _, err := s.GetTrigger(ctx, "t1"); err != ErrTriggerNotFound {
+		t.Fatalf("GetTrigger after delete err = %v, want ErrTriggerNotFound", err)
+	}
+}
+
+func TestMemoryStoreDueTriggersPages(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		id := string(rune('a' + i))
+		due := now.Add(-time.Duration(i) * time.Hour)
+		if err := s.PutTrigger(ctx, &DeadMansTrigger{Id: id, DueToFire: due}); err != nil {
+			t.Fatalf("PutTrigger(%s): %v", id, err)
+		}
+	}
+	// not due yet, should never show up
+	if err := s.PutTrigger(ctx, &DeadMansTrigger{Id: "z", DueToFire: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("PutTrigger(z): %v", err)
+	}
+
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		batch, next, err := s.DueTriggers(ctx, now, cursor, 2)
+		if err != nil {
+			t.Fatalf("DueTriggers: %v", err)
+		}
+		for _, trigger := range batch {
+			if seen[trigger.Id] {
+				t.Fatalf("trigger %s returned twice across pages", trigger.Id)
+			}
+			seen[trigger.Id] = true
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("got %d due triggers across all pages, want 5: %v", len(seen), seen)
+	}
+	if seen["z"] {
+		t.Error("trigger not yet due was returned")
+	}
+}
+
+func TestMemoryStoreTriggersByOwnerPages(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryStore()
+
+	for i := 0; i < 3; i++ {
+		id := string(rune('a' + i))
+		if err := s.PutTrigger(ctx, &DeadMansTrigger{Id: id, OwnerId: "alice"}); err != nil {
+			t.Fatalf("PutTrigger(%s): %v", id, err)
+		}
+	}
+	if err := s.PutTrigger(ctx, &DeadMansTrigger{Id: "x", OwnerId: "bob"}); err != nil {
+		t.Fatalf("PutTrigger(x): %v", err)
+	}
+
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		batch, next, err := s.TriggersByOwner(ctx, "alice", cursor, 2)
+		if err != nil {
+			t.Fatalf("TriggersByOwner: %v", err)
+		}
+		for _, trigger := range batch {
+			seen[trigger.Id] = true
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("got %d triggers for alice across all pages, want 3: %v", len(seen), seen)
+	}
+	if seen["x"] {
+		t.Error("trigger owned by bob was returned for alice's query")
+	}
+}